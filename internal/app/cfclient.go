@@ -0,0 +1,174 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// cfClient resolves Cloudflare zone and DNS record IDs by name, so that
+// records can be configured by `zone` + `record_name` instead of requiring
+// the user to hand-discover `zone_id`/`record_id` out of band.
+type cfClient struct {
+	hc       *http.Client
+	apiToken string
+	reqDeco  func(*http.Request) *http.Request
+}
+
+func newCFClient(hc *http.Client, apiToken string, reqDeco func(*http.Request) *http.Request) *cfClient {
+	return &cfClient{
+		hc:       hc,
+		apiToken: apiToken,
+		reqDeco:  reqDeco,
+	}
+}
+
+type cfAPIResponse struct {
+	Success bool              `json:"success"`
+	Errors  []cfAPIErrorEntry `json:"errors"`
+	Result  json.RawMessage   `json:"result"`
+}
+
+type cfAPIErrorEntry struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (c *cfClient) do(ctx context.Context, method, urlStr string, body []byte) (*cfAPIResponse, int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	} else {
+		reqBody = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create cloudflare api request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req = c.reqDeco(req)
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get response from cloudflare api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result cfAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to decode cloudflare api response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || !result.Success {
+		return &result, resp.StatusCode, fmt.Errorf("cloudflare api request failed with status %d: %s", resp.StatusCode, cfErrorsString(result.Errors))
+	}
+
+	return &result, resp.StatusCode, nil
+}
+
+func cfErrorsString(errs []cfAPIErrorEntry) string {
+	if len(errs) == 0 {
+		return "unknown error"
+	}
+
+	var b bytes.Buffer
+	for i, e := range errs {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(e.Message)
+	}
+	return b.String()
+}
+
+// ResolveZoneID looks up a zone's ID by its domain name.
+func (c *cfClient) ResolveZoneID(ctx context.Context, zoneName string) (string, error) {
+	urlStr := "https://api.cloudflare.com/client/v4/zones?name=" + url.QueryEscape(zoneName)
+
+	result, _, err := c.do(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up zone %q: %w", zoneName, err)
+	}
+
+	var zones []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(result.Result, &zones); err != nil {
+		return "", fmt.Errorf("failed to decode zone lookup result: %w", err)
+	}
+
+	if len(zones) == 0 {
+		return "", fmt.Errorf("no zone found with name %q", zoneName)
+	}
+
+	return zones[0].ID, nil
+}
+
+// ResolveOrCreateRecordID looks up a DNS record's ID by its fully-qualified
+// name and type within a zone, creating a placeholder record if none
+// exists yet.
+func (c *cfClient) ResolveOrCreateRecordID(ctx context.Context, zoneID, recordName, recordType string, ttl int, proxied bool) (string, error) {
+	urlStr := "https://api.cloudflare.com/client/v4/zones/" + url.PathEscape(zoneID) + "/dns_records?name=" + url.QueryEscape(recordName) + "&type=" + url.QueryEscape(recordType)
+
+	result, _, err := c.do(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up record %q: %w", recordName, err)
+	}
+
+	var records []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(result.Result, &records); err != nil {
+		return "", fmt.Errorf("failed to decode record lookup result: %w", err)
+	}
+
+	if len(records) > 0 {
+		return records[0].ID, nil
+	}
+
+	placeholderContent := "0.0.0.0"
+	if recordType == "AAAA" {
+		placeholderContent = "::"
+	}
+
+	body, err := json.Marshal(struct {
+		Type    string `json:"type"`
+		Name    string `json:"name"`
+		Content string `json:"content"`
+		TTL     int    `json:"ttl"`
+		Proxied bool   `json:"proxied"`
+	}{
+		Type:    recordType,
+		Name:    recordName,
+		Content: placeholderContent,
+		TTL:     ttl,
+		Proxied: proxied,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode record creation request: %w", err)
+	}
+
+	createURL := "https://api.cloudflare.com/client/v4/zones/" + url.PathEscape(zoneID) + "/dns_records"
+	result, _, err = c.do(ctx, http.MethodPost, createURL, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create record %q: %w", recordName, err)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(result.Result, &created); err != nil {
+		return "", fmt.Errorf("failed to decode record creation result: %w", err)
+	}
+
+	return created.ID, nil
+}
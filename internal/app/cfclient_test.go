@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(statusCode int, result string) *http.Response {
+	body := `{"success":true,"errors":[],"result":` + result + `}`
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+	}
+}
+
+func identityDeco(r *http.Request) *http.Request { return r }
+
+func TestResolveOrCreateRecordIDReusesExisting(t *testing.T) {
+	var postCalled bool
+
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost {
+			postCalled = true
+			return jsonResponse(http.StatusOK, `{"id":"should-not-be-created"}`), nil
+		}
+		return jsonResponse(http.StatusOK, `[{"id":"existing-record-id"}]`), nil
+	})
+
+	client := newCFClient(&http.Client{Transport: rt}, "token", identityDeco)
+
+	id, err := client.ResolveOrCreateRecordID(context.Background(), "zone-id", "home.example.com", "A", 120, true)
+	if err != nil {
+		t.Fatalf("ResolveOrCreateRecordID() error = %v", err)
+	}
+	if id != "existing-record-id" {
+		t.Errorf("ResolveOrCreateRecordID() = %q, want %q", id, "existing-record-id")
+	}
+	if postCalled {
+		t.Error("ResolveOrCreateRecordID() created a record when one already existed")
+	}
+}
+
+func TestResolveOrCreateRecordIDCreatesWhenMissing(t *testing.T) {
+	var createBody struct {
+		Type    string `json:"type"`
+		Name    string `json:"name"`
+		Content string `json:"content"`
+		TTL     int    `json:"ttl"`
+		Proxied bool   `json:"proxied"`
+	}
+
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost {
+			b, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("failed to read create request body: %v", err)
+			}
+			if err := json.Unmarshal(b, &createBody); err != nil {
+				t.Fatalf("failed to decode create request body: %v", err)
+			}
+			return jsonResponse(http.StatusOK, `{"id":"new-record-id"}`), nil
+		}
+		return jsonResponse(http.StatusOK, `[]`), nil
+	})
+
+	client := newCFClient(&http.Client{Transport: rt}, "token", identityDeco)
+
+	id, err := client.ResolveOrCreateRecordID(context.Background(), "zone-id", "home.example.com", "AAAA", 300, true)
+	if err != nil {
+		t.Fatalf("ResolveOrCreateRecordID() error = %v", err)
+	}
+	if id != "new-record-id" {
+		t.Errorf("ResolveOrCreateRecordID() = %q, want %q", id, "new-record-id")
+	}
+
+	if createBody.Type != "AAAA" || createBody.Name != "home.example.com" || createBody.Content != "::" || createBody.TTL != 300 || !createBody.Proxied {
+		t.Errorf("create request body = %+v, want AAAA/home.example.com/::/300/proxied=true", createBody)
+	}
+}
+
+func TestResolveZoneID(t *testing.T) {
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, `[{"id":"zone-abc"}]`), nil
+	})
+
+	client := newCFClient(&http.Client{Transport: rt}, "token", identityDeco)
+
+	id, err := client.ResolveZoneID(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ResolveZoneID() error = %v", err)
+	}
+	if id != "zone-abc" {
+		t.Errorf("ResolveZoneID() = %q, want %q", id, "zone-abc")
+	}
+}
+
+func TestResolveZoneIDNotFound(t *testing.T) {
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, `[]`), nil
+	})
+
+	client := newCFClient(&http.Client{Transport: rt}, "token", identityDeco)
+
+	if _, err := client.ResolveZoneID(context.Background(), "example.com"); err == nil {
+		t.Error("ResolveZoneID() error = nil, want error for no matching zone")
+	}
+}
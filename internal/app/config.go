@@ -9,20 +9,81 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"strings"
+	"time"
 )
 
-type config struct {
+const defaultRetryBase = 30 * time.Second
+
+type recordConfig struct {
+	Zone       string `json:"zone"`
 	ZoneID     string `json:"zone_id"`
 	RecordID   string `json:"record_id"`
 	RecordName string `json:"record_name"`
-	ApiToken   string `json:"api_token"`
+	Type       string `json:"type"`
 	TTLPtr     *int   `json:"ttl"`
 	ttl        int
-	noCfgFile  bool
+	ProxiedPtr *bool `json:"proxied"`
+	proxied    bool
+}
+
+func (r *recordConfig) isEmpty() bool {
+	return (*r == recordConfig{})
+}
+
+func (r *recordConfig) normalize(ttlDefault int) {
+	if r.Type == "" {
+		r.Type = "A"
+	}
+
+	if r.TTLPtr == nil {
+		r.ttl = ttlDefault
+	} else {
+		r.ttl = *r.TTLPtr
+	}
+
+	if r.ProxiedPtr != nil {
+		r.proxied = *r.ProxiedPtr
+	}
+}
+
+func (r *recordConfig) validate() error {
+	if r.Zone == "" && r.ZoneID == "" {
+		return fmt.Errorf("one of zone or zone_id is required")
+	}
+
+	if r.RecordName == "" {
+		return fmt.Errorf("record_name is required")
+	}
+
+	if r.Type != "A" && r.Type != "AAAA" {
+		return fmt.Errorf("type must be one of A, AAAA, got %q", r.Type)
+	}
+
+	if r.ttl < 1 {
+		return fmt.Errorf("ttl must be greater than 0")
+	}
+
+	return nil
+}
+
+var defaultIPv4Providers = []string{"aws-checkip", "ipify", "icanhazip"}
+var defaultIPv6Providers = []string{"ipify", "icanhazip"}
+
+type config struct {
+	Records          []recordConfig `json:"records"`
+	ApiToken         string         `json:"api_token"`
+	IPv4Providers    []string       `json:"ipv4_providers"`
+	IPv6Providers    []string       `json:"ipv6_providers"`
+	RetryBaseSecsPtr *int           `json:"retry_base_seconds"`
+	retryBase        time.Duration
+	MetricsAddr      string `json:"metrics_addr"`
+
+	noCfgFile bool
 }
 
 func (c *config) isEmpty() bool {
-	return (*c == config{})
+	return len(c.Records) == 0 && c.ApiToken == ""
 }
 
 func (c *config) load(ctx context.Context, logger *slog.Logger, cfgFile string) error {
@@ -60,28 +121,80 @@ func (c *config) load(ctx context.Context, logger *slog.Logger, cfgFile string)
 		emptyCfgFile = result.isEmpty()
 	}
 
-	if result.ZoneID == "" {
+	// a single legacy record may still be configured entirely via
+	// environment variables when no records are declared in the config file
+	if len(result.Records) == 0 {
+		var rc recordConfig
+
+		if v, ok := os.LookupEnv("CLOUDFLARE_ZONE"); ok && v != "" {
+			rc.Zone = v
+		}
+
 		if v, ok := os.LookupEnv("CLOUDFLARE_ZONE_ID"); ok && v != "" {
-			result.ZoneID = v
+			rc.ZoneID = v
 		}
-	}
 
-	if result.RecordID == "" {
 		if v, ok := os.LookupEnv("CLOUDFLARE_RECORD_ID"); ok && v != "" {
-			result.RecordID = v
+			rc.RecordID = v
 		}
-	}
 
-	if result.RecordName == "" {
 		if v, ok := os.LookupEnv("CLOUDFLARE_RECORD_NAME"); ok && v != "" {
-			result.RecordName = v
+			rc.RecordName = v
+		}
+
+		if v, ok := os.LookupEnv("CLOUDFLARE_RECORD_TYPE"); ok && v != "" {
+			rc.Type = v
+		}
+
+		if s, ok := os.LookupEnv("CLOUDFLARE_RECORD_TTL"); ok && s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil || strconv.Itoa(v) != s {
+				msg := "failed to parse CLOUDFLARE_RECORD_TTL environment variable"
+				logger.LogAttrs(ctx, slog.LevelError,
+					msg,
+					errAttr(err),
+				)
+				return fmt.Errorf("%s: %w", msg, err)
+			}
+
+			rc.TTLPtr = &v
+		}
+
+		if !rc.isEmpty() {
+			result.Records = []recordConfig{rc}
+		}
+	}
+
+	for i := range result.Records {
+		result.Records[i].normalize(120)
+	}
+
+	if result.ApiToken == "" {
+		if v, ok := os.LookupEnv("CLOUDFLARE_API_TOKEN"); ok && v != "" {
+			result.ApiToken = v
+		}
+	}
+
+	if len(result.IPv4Providers) == 0 {
+		if v, ok := os.LookupEnv("IPV4_PROVIDERS"); ok && v != "" {
+			result.IPv4Providers = strings.Split(v, ",")
+		} else {
+			result.IPv4Providers = defaultIPv4Providers
 		}
 	}
 
-	if s, ok := os.LookupEnv("CLOUDFLARE_RECORD_TTL"); ok && s != "" {
+	if len(result.IPv6Providers) == 0 {
+		if v, ok := os.LookupEnv("IPV6_PROVIDERS"); ok && v != "" {
+			result.IPv6Providers = strings.Split(v, ",")
+		} else {
+			result.IPv6Providers = defaultIPv6Providers
+		}
+	}
+
+	if s, ok := os.LookupEnv("RETRY_BASE_SECONDS"); ok && s != "" {
 		v, err := strconv.Atoi(s)
 		if err != nil || strconv.Itoa(v) != s {
-			msg := "failed to parse CLOUDFLARE_RECORD_TTL environment variable"
+			msg := "failed to parse RETRY_BASE_SECONDS environment variable"
 			logger.LogAttrs(ctx, slog.LevelError,
 				msg,
 				errAttr(err),
@@ -89,16 +202,16 @@ func (c *config) load(ctx context.Context, logger *slog.Logger, cfgFile string)
 			return fmt.Errorf("%s: %w", msg, err)
 		}
 
-		result.ttl = v
-	} else if result.TTLPtr == nil {
-		result.ttl = 120
+		result.retryBase = time.Duration(v) * time.Second
+	} else if result.RetryBaseSecsPtr == nil {
+		result.retryBase = defaultRetryBase
 	} else {
-		result.ttl = *result.TTLPtr
+		result.retryBase = time.Duration(*result.RetryBaseSecsPtr) * time.Second
 	}
 
-	if result.ApiToken == "" {
-		if v, ok := os.LookupEnv("CLOUDFLARE_API_TOKEN"); ok && v != "" {
-			result.ApiToken = v
+	if result.MetricsAddr == "" {
+		if v, ok := os.LookupEnv("METRICS_ADDR"); ok && v != "" {
+			result.MetricsAddr = v
 		}
 	}
 
@@ -151,20 +264,30 @@ func (c *config) validate(ctx context.Context, logger *slog.Logger) (_err error)
 		return fmt.Errorf("CLOUDFLARE_API_TOKEN is required")
 	}
 
-	if c.ZoneID == "" {
-		return fmt.Errorf("CLOUDFLARE_ZONE_ID is required")
+	if len(c.Records) == 0 {
+		return fmt.Errorf("at least one record must be configured")
 	}
 
-	if c.RecordID == "" {
-		return fmt.Errorf("CLOUDFLARE_RECORD_ID is required")
+	for i := range c.Records {
+		if err := c.Records[i].validate(); err != nil {
+			return fmt.Errorf("records[%d]: %w", i, err)
+		}
 	}
 
-	if c.RecordName == "" {
-		return fmt.Errorf("CLOUDFLARE_RECORD_NAME is required")
+	for _, name := range c.IPv4Providers {
+		if _, err := newIPProviderByName(name, ipFamilyV4); err != nil {
+			return fmt.Errorf("ipv4_providers: %w", err)
+		}
 	}
 
-	if c.ttl < 1 {
-		return fmt.Errorf("CLOUDFLARE_RECORD_TTL must be greater than 0")
+	for _, name := range c.IPv6Providers {
+		if _, err := newIPProviderByName(name, ipFamilyV6); err != nil {
+			return fmt.Errorf("ipv6_providers: %w", err)
+		}
+	}
+
+	if c.retryBase < 0 {
+		return fmt.Errorf("retry_base_seconds must not be negative")
 	}
 
 	return nil
@@ -182,19 +305,29 @@ func (c *config) Load(ctx context.Context, logger *slog.Logger, cfgFile string)
 	return nil
 }
 
-func newConfig(ctx context.Context, logger *slog.Logger) (config, error) {
-	var v, result config
-
-	var stateDir string
+func configDir() string {
 	if v, ok := os.LookupEnv("CONFIG_DIR"); ok && v != "" {
-		stateDir = v
+		return v
 	}
+	return ""
+}
 
+func configFilePath() string {
 	cfgFile := "config.json"
+
+	stateDir := configDir()
 	if stateDir != "" && stateDir != "." {
 		cfgFile = path.Join(stateDir, cfgFile)
 	}
 
+	return cfgFile
+}
+
+func newConfig(ctx context.Context, logger *slog.Logger) (config, error) {
+	var v, result config
+
+	cfgFile := configFilePath()
+
 	if err := v.Load(ctx, logger, cfgFile); err != nil {
 		return result, err
 	}
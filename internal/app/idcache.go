@@ -0,0 +1,62 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+)
+
+// idCacheEntry holds the Cloudflare IDs resolved for one configured record,
+// keyed so that a later run with the same zone/record/type can skip the
+// name-lookup round trips.
+type idCacheEntry struct {
+	ZoneID   string `json:"zone_id"`
+	RecordID string `json:"record_id"`
+}
+
+type idCache map[string]idCacheEntry
+
+func idCacheKey(zone, recordName, recordType string) string {
+	return zone + "|" + recordName + "|" + recordType
+}
+
+func idCacheFilePath(stateDir string) string {
+	const fileName = "id_cache.json"
+
+	if stateDir == "" || stateDir == "." {
+		return fileName
+	}
+
+	return path.Join(stateDir, fileName)
+}
+
+func loadIDCache(filePath string) (idCache, error) {
+	c := idCache{}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c idCache) save(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIDCacheKey(t *testing.T) {
+	got := idCacheKey("example.com", "home.example.com", "A")
+	want := "example.com|home.example.com|A"
+	if got != want {
+		t.Errorf("idCacheKey() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadIDCacheMissingFile(t *testing.T) {
+	c, err := loadIDCache(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("loadIDCache() error = %v, want nil", err)
+	}
+	if len(c) != 0 {
+		t.Errorf("loadIDCache() = %v, want empty cache", c)
+	}
+}
+
+func TestIDCacheSaveLoadRoundTrip(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "id_cache.json")
+
+	key := idCacheKey("example.com", "home.example.com", "A")
+	c := idCache{
+		key: idCacheEntry{ZoneID: "zone-123", RecordID: "record-456"},
+	}
+
+	if err := c.save(filePath); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	got, err := loadIDCache(filePath)
+	if err != nil {
+		t.Fatalf("loadIDCache() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("loadIDCache() = %v, want 1 entry", got)
+	}
+
+	entry, ok := got[key]
+	if !ok {
+		t.Fatalf("loadIDCache() missing key %q", key)
+	}
+	if entry != c[key] {
+		t.Errorf("loadIDCache() entry = %+v, want %+v", entry, c[key])
+	}
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPProvider discovers the machine's current public IP address for a single
+// address family (IPv4 or IPv6).
+type IPProvider interface {
+	// Name identifies the provider in logs and config (e.g. "aws-checkip").
+	Name() string
+
+	// LookupIP returns the discovered address, or an error if it could not
+	// be determined. Implementations must return an address matching the
+	// family they were constructed for.
+	LookupIP(ctx context.Context, hc *http.Client, reqDeco func(*http.Request) *http.Request) (net.IP, error)
+}
+
+// httpIPProvider discovers the public IP by GETing a URL that echoes the
+// caller's address back as a bare string in the response body.
+type httpIPProvider struct {
+	name    string
+	baseReq *http.Request
+	family  ipFamily
+}
+
+type ipFamily int
+
+const (
+	ipFamilyV4 ipFamily = iota
+	ipFamilyV6
+)
+
+func newHTTPIPProvider(name, urlStr string, family ipFamily) *httpIPProvider {
+	req, err := http.NewRequest(http.MethodGet, urlStr, http.NoBody)
+	if err != nil {
+		panic(fmt.Errorf("failed to create base request for %s IP provider: %w", name, err))
+	}
+
+	return &httpIPProvider{
+		name:    name,
+		baseReq: req,
+		family:  family,
+	}
+}
+
+func (p *httpIPProvider) Name() string {
+	return p.name
+}
+
+func (p *httpIPProvider) LookupIP(ctx context.Context, hc *http.Client, reqDeco func(*http.Request) *http.Request) (net.IP, error) {
+	req := reqDeco(p.baseReq.Clone(ctx))
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response from %s: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("response status code from %s is not in 2xx range: %d", p.name, resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(string(b)))
+	if ip == nil {
+		return nil, fmt.Errorf("%s failed to return a valid IP address in response body", p.name)
+	}
+
+	if err := checkIPFamily(ip, p.family); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.name, err)
+	}
+
+	return ip, nil
+}
+
+// osIPProvider discovers the public IP by enumerating local interface
+// addresses and picking the first global unicast address of the configured
+// family. It is useful on hosts with a routable address and no NAT.
+type osIPProvider struct {
+	family ipFamily
+}
+
+func newOSIPProvider(family ipFamily) *osIPProvider {
+	return &osIPProvider{family: family}
+}
+
+func (p *osIPProvider) Name() string {
+	return "os"
+}
+
+func (p *osIPProvider) LookupIP(context.Context, *http.Client, func(*http.Request) *http.Request) (net.IP, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to enumerate interface addresses: %w", err)
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		ip := ipNet.IP
+		if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+			continue
+		}
+
+		if checkIPFamily(ip, p.family) != nil {
+			continue
+		}
+
+		return ip, nil
+	}
+
+	return nil, errors.New("no routable global unicast address found for the configured family")
+}
+
+func checkIPFamily(ip net.IP, family ipFamily) error {
+	is4 := ip.To4() != nil
+
+	switch family {
+	case ipFamilyV4:
+		if !is4 {
+			return errors.New("expected an IPv4 address, got an IPv6 address")
+		}
+	case ipFamilyV6:
+		if is4 {
+			return errors.New("expected an IPv6 address, got an IPv4 address")
+		}
+	}
+
+	return nil
+}
+
+func newIPProviderByName(name string, family ipFamily) (IPProvider, error) {
+	switch name {
+	case "aws-checkip":
+		if family == ipFamilyV6 {
+			return nil, fmt.Errorf("IP provider %q does not support IPv6", name)
+		}
+		return newHTTPIPProvider(name, familyURL(family, "https://checkip.amazonaws.com/", ""), family), nil
+	case "ipify":
+		return newHTTPIPProvider(name, familyURL(family, "https://api.ipify.org", "https://api6.ipify.org"), family), nil
+	case "icanhazip":
+		return newHTTPIPProvider(name, familyURL(family, "https://ipv4.icanhazip.com", "https://ipv6.icanhazip.com"), family), nil
+	case "os":
+		return newOSIPProvider(family), nil
+	default:
+		return nil, fmt.Errorf("unknown IP provider %q", name)
+	}
+}
+
+func familyLabel(family ipFamily) string {
+	if family == ipFamilyV6 {
+		return "v6"
+	}
+	return "v4"
+}
+
+func familyURL(family ipFamily, v4URL, v6URL string) string {
+	if family == ipFamilyV6 {
+		return v6URL
+	}
+	return v4URL
+}
+
+// lookupIPWithFallback tries each provider in order, returning the first
+// successfully discovered address. All provider errors are joined and
+// returned if none succeed.
+func lookupIPWithFallback(ctx context.Context, logger *slog.Logger, providers []IPProvider, hc *http.Client, reqDeco func(*http.Request) *http.Request) (net.IP, error) {
+	var errs []error
+
+	for _, p := range providers {
+		ip, err := p.LookupIP(ctx, hc, reqDeco)
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn,
+				"IP provider failed",
+				slog.String("provider", p.Name()),
+				errAttr(err),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+
+		return ip, nil
+	}
+
+	return nil, fmt.Errorf("all IP providers failed: %w", errors.Join(errs...))
+}
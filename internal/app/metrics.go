@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metrics holds the Prometheus collectors exposed by the embedded /metrics
+// endpoint, along with the health bookkeeping surfaced on /healthz and
+// /readyz.
+type metrics struct {
+	registry *prometheus.Registry
+
+	syncAttemptsTotal prometheus.Counter
+	syncFailuresTotal *prometheus.CounterVec
+	ipChangesTotal    *prometheus.CounterVec
+	lastSyncTimestamp prometheus.Gauge
+	currentIPInfo     *prometheus.GaugeVec
+
+	mu                   sync.Mutex
+	consecutiveFailCount int
+	lastSuccess          time.Time
+	currentIPByFamily    map[string]string
+}
+
+func newMetrics() *metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &metrics{
+		registry: registry,
+		syncAttemptsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "sync_attempts_total",
+			Help: "Total number of sync attempts.",
+		}),
+		syncFailuresTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sync_failures_total",
+			Help: "Total number of sync failures, labeled by reason.",
+		}, []string{"reason"}),
+		ipChangesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ip_changes_total",
+			Help: "Total number of detected public IP address changes, labeled by address family.",
+		}, []string{"family"}),
+		lastSyncTimestamp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "last_sync_timestamp_seconds",
+			Help: "Unix timestamp of the last successful sync.",
+		}),
+		currentIPInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "current_ip_info",
+			Help: "Current discovered public IP address, labeled by ip and family; value is always 1.",
+		}, []string{"ip", "family"}),
+	}
+
+	registry.MustRegister(
+		m.syncAttemptsTotal,
+		m.syncFailuresTotal,
+		m.ipChangesTotal,
+		m.lastSyncTimestamp,
+		m.currentIPInfo,
+	)
+
+	return m
+}
+
+func (m *metrics) recordAttempt() {
+	m.syncAttemptsTotal.Inc()
+}
+
+func (m *metrics) recordFailure(reason string) {
+	m.syncFailuresTotal.WithLabelValues(reason).Inc()
+
+	m.mu.Lock()
+	m.consecutiveFailCount++
+	m.mu.Unlock()
+}
+
+func (m *metrics) recordSuccess(now time.Time) {
+	m.lastSyncTimestamp.Set(float64(now.Unix()))
+
+	m.mu.Lock()
+	m.consecutiveFailCount = 0
+	m.lastSuccess = now
+	m.mu.Unlock()
+}
+
+func (m *metrics) recordIP(family, ip string) {
+	m.mu.Lock()
+	if m.currentIPByFamily == nil {
+		m.currentIPByFamily = make(map[string]string, 2)
+	}
+	prev, changed := m.currentIPByFamily[family], false
+	if prev != ip {
+		changed = true
+		if prev != "" {
+			m.currentIPInfo.DeleteLabelValues(prev, family)
+		}
+		m.currentIPByFamily[family] = ip
+	}
+	m.mu.Unlock()
+
+	m.currentIPInfo.WithLabelValues(ip, family).Set(1)
+
+	if changed {
+		m.ipChangesTotal.WithLabelValues(family).Inc()
+	}
+}
+
+func (m *metrics) health() (consecutiveFailCount int, lastSuccess time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.consecutiveFailCount, m.lastSuccess
+}
+
+// startMetricsServer starts the embedded HTTP server exposing /metrics,
+// /healthz, and /readyz, returning a shutdown func to be called on context
+// cancellation. It is a no-op if addr is empty.
+func startMetricsServer(ctx context.Context, logger *slog.Logger, addr string, m *metrics) (func(context.Context) error, error) {
+	if addr == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, m, false)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, m, true)
+	})
+
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.LogAttrs(ctx, slog.LevelError,
+				"metrics server exited unexpectedly",
+				errAttr(err),
+			)
+		}
+	}()
+
+	logger.LogAttrs(ctx, slog.LevelInfo,
+		"metrics server listening",
+		slog.String("addr", addr),
+	)
+
+	return srv.Shutdown, nil
+}
+
+// writeHealth reports service health as JSON. /healthz reports liveness
+// (the process is running its loop); /readyz additionally requires a
+// recent successful sync, since a daemon that never successfully synced is
+// not ready to serve.
+func writeHealth(w http.ResponseWriter, m *metrics, readiness bool) {
+	consecutiveFailCount, lastSuccess := m.health()
+
+	healthy := true
+	if readiness {
+		healthy = !lastSuccess.IsZero() && consecutiveFailCount == 0
+	}
+
+	status := http.StatusOK
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(struct {
+		Healthy              bool      `json:"healthy"`
+		ConsecutiveFailCount int       `json:"consecutive_fail_count"`
+		LastSuccess          time.Time `json:"last_success"`
+	}{
+		Healthy:              healthy,
+		ConsecutiveFailCount: consecutiveFailCount,
+		LastSuccess:          lastSuccess,
+	})
+}
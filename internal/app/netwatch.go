@@ -0,0 +1,25 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+)
+
+// netChangeWatcher notifies on C() whenever the local network configuration
+// (interface addresses, link state) changes, so that a sync can be
+// triggered immediately instead of waiting for the next ticker.
+type netChangeWatcher interface {
+	// C delivers a value each time a relevant network change is observed.
+	// Sends are best-effort; a slow reader may coalesce multiple changes
+	// into a single notification.
+	C() <-chan struct{}
+
+	Close() error
+}
+
+// newNetChangeWatcher starts the platform-appropriate network change
+// watcher: a netlink route/address subscription on Linux, and an interface
+// address polling loop everywhere else.
+func newNetChangeWatcher(ctx context.Context, logger *slog.Logger) (netChangeWatcher, error) {
+	return newPlatformNetChangeWatcher(ctx, logger)
+}
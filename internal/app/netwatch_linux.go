@@ -0,0 +1,98 @@
+//go:build linux
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// linuxNetChangeWatcher subscribes to RTMGRP_IPV4_IFADDR/RTMGRP_IPV6_IFADDR
+// and RTMGRP_LINK events on a netlink route socket, firing on C() the
+// moment the kernel reports an address or link change.
+type linuxNetChangeWatcher struct {
+	fd int
+	ch chan struct{}
+}
+
+func newPlatformNetChangeWatcher(ctx context.Context, logger *slog.Logger) (netChangeWatcher, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open netlink route socket: %w", err)
+	}
+
+	addr := &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: unix.RTMGRP_IPV4_IFADDR | unix.RTMGRP_IPV6_IFADDR | unix.RTMGRP_LINK,
+	}
+	if err := unix.Bind(fd, addr); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to bind netlink route socket: %w", err)
+	}
+
+	w := &linuxNetChangeWatcher{
+		fd: fd,
+		ch: make(chan struct{}, 1),
+	}
+
+	go w.readLoop(ctx, logger)
+
+	return w, nil
+}
+
+func (w *linuxNetChangeWatcher) readLoop(ctx context.Context, logger *slog.Logger) {
+	buf := make([]byte, 8192)
+
+	for {
+		n, _, err := unix.Recvfrom(w.fd, buf, 0)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.LogAttrs(ctx, slog.LevelWarn,
+				"netlink read failed",
+				errAttr(err),
+			)
+			return
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn,
+				"failed to parse netlink message",
+				errAttr(err),
+			)
+			continue
+		}
+
+		relevant := false
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case unix.RTM_NEWADDR, unix.RTM_DELADDR, unix.RTM_NEWLINK, unix.RTM_DELLINK:
+				relevant = true
+			}
+		}
+
+		if !relevant {
+			continue
+		}
+
+		select {
+		case w.ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (w *linuxNetChangeWatcher) C() <-chan struct{} {
+	return w.ch
+}
+
+func (w *linuxNetChangeWatcher) Close() error {
+	return unix.Close(w.fd)
+}
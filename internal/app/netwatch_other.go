@@ -0,0 +1,110 @@
+//go:build !linux
+
+package app
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+)
+
+// pollingNetChangeWatcher polls local interface addresses on an interval
+// and fires C() when the observed set changes. Used on platforms without a
+// netlink-equivalent event source.
+type pollingNetChangeWatcher struct {
+	ch     chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+const netChangePollInterval = 30 * time.Second
+
+func newPlatformNetChangeWatcher(ctx context.Context, logger *slog.Logger) (netChangeWatcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w := &pollingNetChangeWatcher{
+		ch:     make(chan struct{}, 1),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go w.pollLoop(ctx, logger)
+
+	return w, nil
+}
+
+func (w *pollingNetChangeWatcher) pollLoop(ctx context.Context, logger *slog.Logger) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(netChangePollInterval)
+	defer ticker.Stop()
+
+	prev, err := currentAddrSet()
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn,
+			"failed initial interface address snapshot",
+			errAttr(err),
+		)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cur, err := currentAddrSet()
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn,
+				"failed to enumerate interface addresses",
+				errAttr(err),
+			)
+			continue
+		}
+
+		if !addrSetsEqual(prev, cur) {
+			prev = cur
+			select {
+			case w.ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func currentAddrSet() (map[string]bool, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(addrs))
+	for _, a := range addrs {
+		set[a.String()] = true
+	}
+	return set, nil
+}
+
+func addrSetsEqual(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *pollingNetChangeWatcher) C() <-chan struct{} {
+	return w.ch
+}
+
+func (w *pollingNetChangeWatcher) Close() error {
+	w.cancel()
+	<-w.done
+	return nil
+}
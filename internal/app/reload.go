@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadSource merges SIGHUP and (optionally) config file write events into
+// a single channel run's main loop can wait on to trigger a config reload.
+type reloadSource struct {
+	ch         chan struct{}
+	sigChan    chan os.Signal
+	watcher    *fsnotify.Watcher
+	cfgFileAbs string
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func newReloadSource(ctx context.Context, logger *slog.Logger, cfgFile string) *reloadSource {
+	rs := &reloadSource{
+		ch:      make(chan struct{}, 1),
+		sigChan: make(chan os.Signal, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	signal.Notify(rs.sigChan, syscall.SIGHUP)
+
+	if cfgFile != "" {
+		// Watch the containing directory rather than the file itself: editors
+		// and deployment tools typically rewrite a config file by creating a
+		// new inode and renaming it over the old one, which would silently
+		// detach a watch held on the file directly (see fsnotify's "Watching
+		// a file doesn't work well" caveat).
+		cfgFileAbs, err := filepath.Abs(cfgFile)
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn,
+				"failed to resolve absolute config file path, reload will only trigger on SIGHUP",
+				errAttr(err),
+			)
+			cfgFileAbs = ""
+		}
+
+		if cfgFileAbs != "" {
+			watcher, err := fsnotify.NewWatcher()
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelWarn,
+					"failed to start config file watcher, reload will only trigger on SIGHUP",
+					errAttr(err),
+				)
+			} else if err := watcher.Add(filepath.Dir(cfgFileAbs)); err != nil {
+				logger.LogAttrs(ctx, slog.LevelWarn,
+					"failed to watch config file directory, reload will only trigger on SIGHUP",
+					errAttr(err),
+				)
+				_ = watcher.Close()
+			} else {
+				rs.watcher = watcher
+				rs.cfgFileAbs = cfgFileAbs
+			}
+		}
+	}
+
+	go rs.run()
+
+	return rs
+}
+
+func (rs *reloadSource) run() {
+	defer close(rs.done)
+
+	var events <-chan fsnotify.Event
+	var watchErrs <-chan error
+	if rs.watcher != nil {
+		events = rs.watcher.Events
+		watchErrs = rs.watcher.Errors
+	}
+
+	for {
+		select {
+		case <-rs.stop:
+			return
+		case _, ok := <-rs.sigChan:
+			if !ok {
+				return
+			}
+			rs.notify()
+		case ev, ok := <-events:
+			if ok && filepath.Clean(ev.Name) == rs.cfgFileAbs && ev.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				rs.notify()
+			}
+		case <-watchErrs:
+			// the watcher itself misbehaving shouldn't take down reload via SIGHUP
+		}
+	}
+}
+
+func (rs *reloadSource) notify() {
+	select {
+	case rs.ch <- struct{}{}:
+	default:
+	}
+}
+
+// C delivers a value each time a reload has been requested.
+func (rs *reloadSource) C() <-chan struct{} {
+	return rs.ch
+}
+
+func (rs *reloadSource) Close() {
+	close(rs.stop)
+	signal.Stop(rs.sigChan)
+	if rs.watcher != nil {
+		_ = rs.watcher.Close()
+	}
+	<-rs.done
+}
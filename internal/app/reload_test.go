@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func waitForReload(t *testing.T, rs *reloadSource, timeout time.Duration) bool {
+	t.Helper()
+
+	select {
+	case <-rs.C():
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func TestReloadSourceTriggersOnAtomicConfigRewrite(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "config.json")
+
+	if err := os.WriteFile(cfgFile, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("failed to write initial config: %v", err)
+	}
+
+	rs := newReloadSource(context.Background(), discardLogger(), cfgFile)
+	defer rs.Close()
+
+	if rs.watcher == nil {
+		t.Fatal("reloadSource did not start a file watcher")
+	}
+
+	// simulate an editor/deploy tool rewriting the config atomically: write
+	// to a temp file in the same directory, then rename over the target.
+	tmpFile := filepath.Join(dir, "config.json.tmp")
+	if err := os.WriteFile(tmpFile, []byte(`{"api_token":"x"}`), 0o644); err != nil {
+		t.Fatalf("failed to write replacement config: %v", err)
+	}
+	if err := os.Rename(tmpFile, cfgFile); err != nil {
+		t.Fatalf("failed to rename replacement config into place: %v", err)
+	}
+
+	if !waitForReload(t, rs, 2*time.Second) {
+		t.Fatal("expected reload to trigger after atomic config rewrite, got nothing")
+	}
+}
+
+func TestReloadSourceIgnoresUnrelatedFilesInSameDirectory(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "config.json")
+
+	if err := os.WriteFile(cfgFile, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("failed to write initial config: %v", err)
+	}
+
+	rs := newReloadSource(context.Background(), discardLogger(), cfgFile)
+	defer rs.Close()
+
+	unrelated := filepath.Join(dir, "id_cache.json")
+	if err := os.WriteFile(unrelated, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("failed to write unrelated file: %v", err)
+	}
+	if err := os.WriteFile(unrelated, []byte(`{"updated":true}`), 0o644); err != nil {
+		t.Fatalf("failed to rewrite unrelated file: %v", err)
+	}
+
+	if waitForReload(t, rs, 500*time.Millisecond) {
+		t.Fatal("expected no reload from writes to an unrelated file in the watched directory")
+	}
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// permanentError marks a sync failure that retrying sooner than the normal
+// sync interval will not resolve (e.g. invalid credentials, a malformed
+// request). The retry policy falls back to the regular cadence for these
+// instead of backing off and retrying quickly.
+type permanentError struct {
+	err error
+}
+
+func newPermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func isPermanentLeaf(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
+
+// flattenErr descends through err's errors.Join tree, one leaf per joined
+// error. newSyncFunc joins one error per failed record into a single sync
+// error, so callers that need to classify individual record failures
+// (rather than the tick as a whole) walk the tree via this helper instead
+// of treating the joined error as a single classification.
+func flattenErr(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	j, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+
+	var leaves []error
+	for _, e := range j.Unwrap() {
+		leaves = append(leaves, flattenErr(e)...)
+	}
+	return leaves
+}
+
+// isPermanent reports whether every record failure within err is permanent.
+// A mix of failures (e.g. one record's bad API token alongside another's
+// transient network blip) must not be reported as permanent overall, since
+// that would suppress the fast retry the transient failure needs.
+func isPermanent(err error) bool {
+	leaves := flattenErr(err)
+	if len(leaves) == 0 {
+		return false
+	}
+
+	for _, leaf := range leaves {
+		if !isPermanentLeaf(leaf) {
+			return false
+		}
+	}
+	return true
+}
+
+// retryAfterError carries a server-specified minimum delay before the next
+// attempt, as returned by Cloudflare on a 429 response.
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+func newRetryAfterError(err error, after time.Duration) error {
+	return &retryAfterError{err: err, after: after}
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+func retryAfterFrom(err error) (time.Duration, bool) {
+	var rae *retryAfterError
+	if errors.As(err, &rae) {
+		return rae.after, true
+	}
+	return 0, false
+}
+
+// classifyHTTPError wraps an HTTP-derived sync error with the retry
+// classification implied by the response it came from: permanent for 4xx
+// (other than 429), honoring Retry-After for 429, and left as-is (transient)
+// for everything else, including network-level failures.
+func classifyHTTPError(err error, statusCode int, header http.Header) error {
+	if err == nil {
+		return nil
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		if d, ok := parseRetryAfter(header); ok {
+			return newRetryAfterError(err, d)
+		}
+		return err
+	}
+
+	if statusCode >= 400 && statusCode < 500 {
+		return newPermanentError(err)
+	}
+
+	return err
+}
+
+func parseRetryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+
+	return 0, false
+}
+
+// retryPolicy computes the delay before the next sync attempt after a
+// transient failure, using exponential backoff with full jitter between
+// attempts, capped so it never exceeds the normal sync interval.
+//
+// nextDelay expects consecutiveFailCount as reported by the caller after
+// incrementing on the current failure (so 1 means "just failed for the
+// first time"), and backs off as base*2^(consecutiveFailCount-1).
+type retryPolicy struct {
+	base time.Duration
+	cap  time.Duration
+}
+
+// syncFailureReason classifies a sync error into a short, low-cardinality
+// label suitable for the sync_failures_total{reason=...} metric. When err
+// joins multiple record failures, the label reflects the most urgent
+// classification present across them (transient, then rate_limited, then
+// permanent only if every record failure was permanent).
+func syncFailureReason(err error) string {
+	reasons := make(map[string]bool, 1)
+	for _, leaf := range flattenErr(err) {
+		reasons[leafFailureReason(leaf)] = true
+	}
+
+	switch {
+	case reasons["transient"]:
+		return "transient"
+	case reasons["rate_limited"]:
+		return "rate_limited"
+	default:
+		return "permanent"
+	}
+}
+
+func leafFailureReason(err error) string {
+	if _, ok := retryAfterFrom(err); ok {
+		return "rate_limited"
+	}
+
+	if isPermanentLeaf(err) {
+		return "permanent"
+	}
+
+	return "transient"
+}
+
+func (p retryPolicy) nextDelay(consecutiveFailCount int) time.Duration {
+	if p.base <= 0 {
+		return 0
+	}
+
+	backoff := p.base
+	for i := 0; i < consecutiveFailCount-1 && backoff < p.cap; i++ {
+		backoff *= 2
+	}
+	if backoff > p.cap {
+		backoff = p.cap
+	}
+
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
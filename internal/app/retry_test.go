@@ -0,0 +1,240 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClassifyHTTPError(t *testing.T) {
+	baseErr := errors.New("unexpected response status code from cloudflare: 429")
+
+	tests := []struct {
+		name              string
+		statusCode        int
+		header            http.Header
+		wantPermanent     bool
+		wantRetryAfter    time.Duration
+		wantHasRetryAfter bool
+	}{
+		{
+			name:              "429 with Retry-After seconds",
+			statusCode:        http.StatusTooManyRequests,
+			header:            http.Header{"Retry-After": []string{"30"}},
+			wantRetryAfter:    30 * time.Second,
+			wantHasRetryAfter: true,
+		},
+		{
+			name:       "429 without Retry-After",
+			statusCode: http.StatusTooManyRequests,
+			header:     http.Header{},
+		},
+		{
+			name:          "400 is permanent",
+			statusCode:    http.StatusBadRequest,
+			header:        http.Header{},
+			wantPermanent: true,
+		},
+		{
+			name:          "403 is permanent",
+			statusCode:    http.StatusForbidden,
+			header:        http.Header{},
+			wantPermanent: true,
+		},
+		{
+			name:       "500 is transient",
+			statusCode: http.StatusInternalServerError,
+			header:     http.Header{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyHTTPError(baseErr, tt.statusCode, tt.header)
+
+			if got := isPermanent(err); got != tt.wantPermanent {
+				t.Errorf("isPermanent() = %v, want %v", got, tt.wantPermanent)
+			}
+
+			after, ok := retryAfterFrom(err)
+			if ok != tt.wantHasRetryAfter {
+				t.Errorf("retryAfterFrom() ok = %v, want %v", ok, tt.wantHasRetryAfter)
+			}
+			if ok && after != tt.wantRetryAfter {
+				t.Errorf("retryAfterFrom() = %v, want %v", after, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "missing header",
+			header: http.Header{},
+		},
+		{
+			name:   "seconds",
+			header: http.Header{"Retry-After": []string{"120"}},
+			want:   120 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "negative seconds rejected",
+			header: http.Header{"Retry-After": []string{"-5"}},
+		},
+		{
+			name:   "garbage value rejected",
+			header: http.Header{"Retry-After": []string{"not-a-date"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.header)
+			if ok != tt.wantOK {
+				t.Errorf("parseRetryAfter() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("parseRetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNextDelay(t *testing.T) {
+	policy := retryPolicy{base: 30 * time.Second, cap: 4 * time.Minute}
+
+	tests := []struct {
+		name                 string
+		consecutiveFailCount int
+		wantMax              time.Duration
+	}{
+		{
+			name:                 "first failure stays within base",
+			consecutiveFailCount: 1,
+			wantMax:              30 * time.Second,
+		},
+		{
+			name:                 "second failure doubles once",
+			consecutiveFailCount: 2,
+			wantMax:              60 * time.Second,
+		},
+		{
+			name:                 "third failure doubles twice",
+			consecutiveFailCount: 3,
+			wantMax:              120 * time.Second,
+		},
+		{
+			name:                 "large fail count is capped",
+			consecutiveFailCount: 20,
+			wantMax:              4 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got := policy.nextDelay(tt.consecutiveFailCount)
+				if got < 0 || got > tt.wantMax {
+					t.Fatalf("nextDelay(%d) = %v, want in [0, %v]", tt.consecutiveFailCount, got, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestIsPermanentJoinedErrors(t *testing.T) {
+	permanent := newPermanentError(errors.New("bad token"))
+	transient := errors.New("network blip")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "single permanent error",
+			err:  permanent,
+			want: true,
+		},
+		{
+			name: "single transient error",
+			err:  transient,
+			want: false,
+		},
+		{
+			name: "all joined errors permanent",
+			err:  errors.Join(permanent, newPermanentError(errors.New("also bad"))),
+			want: true,
+		},
+		{
+			name: "mixed permanent and transient must not be permanent",
+			err:  errors.Join(permanent, transient),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermanent(tt.err); got != tt.want {
+				t.Errorf("isPermanent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncFailureReasonJoinedErrors(t *testing.T) {
+	permanent := newPermanentError(errors.New("bad token"))
+	transient := errors.New("network blip")
+	rateLimited := newRetryAfterError(errors.New("too many requests"), 30*time.Second)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "all permanent",
+			err:  errors.Join(permanent, newPermanentError(errors.New("also bad"))),
+			want: "permanent",
+		},
+		{
+			name: "permanent mixed with transient favors transient",
+			err:  errors.Join(permanent, transient),
+			want: "transient",
+		},
+		{
+			name: "permanent mixed with rate limited favors rate limited",
+			err:  errors.Join(permanent, rateLimited),
+			want: "rate_limited",
+		},
+		{
+			name: "rate limited mixed with transient favors transient",
+			err:  errors.Join(rateLimited, transient),
+			want: "transient",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := syncFailureReason(tt.err); got != tt.want {
+				t.Errorf("syncFailureReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyNextDelayZeroBase(t *testing.T) {
+	policy := retryPolicy{base: 0, cap: time.Minute}
+
+	if got := policy.nextDelay(5); got != 0 {
+		t.Errorf("nextDelay() = %v, want 0", got)
+	}
+}
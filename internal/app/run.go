@@ -43,9 +43,101 @@ func Run(ctx context.Context) error {
 		return fmt.Errorf("%s: %w", msg, err)
 	}
 
+	resolveHC := &http.Client{Timeout: 10 * time.Second}
+	defer resolveHC.CloseIdleConnections()
+
+	reqDeco := func(r *http.Request) *http.Request {
+		r.Header.Set("User-Agent", "github.com---josephcopenhaver--cloudflare-dns-sync/1.0")
+		return r
+	}
+
+	if err := resolveConfigIDs(ctx, logger, &cfg, resolveHC, reqDeco); err != nil {
+		const msg = "failed to resolve zone/record IDs"
+		logger.LogAttrs(ctx, slog.LevelError,
+			msg,
+			errAttr(err),
+		)
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
 	return run(ctx, logger, cfg)
 }
 
+// resolveConfigIDs fills in any missing zone_id/record_id values for
+// records configured by name, consulting an on-disk cache first and
+// falling back to the Cloudflare API, creating the DNS record if it does
+// not exist yet. Resolved IDs are written back to the cache for reuse on
+// the next start.
+func resolveConfigIDs(ctx context.Context, logger *slog.Logger, cfg *config, hc *http.Client, reqDeco func(*http.Request) *http.Request) error {
+	needsResolution := false
+	for i := range cfg.Records {
+		if cfg.Records[i].ZoneID == "" || cfg.Records[i].RecordID == "" {
+			needsResolution = true
+			break
+		}
+	}
+	if !needsResolution {
+		return nil
+	}
+
+	cacheFile := idCacheFilePath(configDir())
+	cache, err := loadIDCache(cacheFile)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn,
+			"failed to load zone/record ID cache, resolving from scratch",
+			errAttr(err),
+		)
+		cache = idCache{}
+	}
+
+	client := newCFClient(hc, cfg.ApiToken, reqDeco)
+	changed := false
+
+	for i := range cfg.Records {
+		rc := &cfg.Records[i]
+
+		if rc.ZoneID != "" && rc.RecordID != "" {
+			continue
+		}
+
+		key := idCacheKey(rc.Zone, rc.RecordName, rc.Type)
+		if entry, ok := cache[key]; ok {
+			rc.ZoneID = entry.ZoneID
+			rc.RecordID = entry.RecordID
+			continue
+		}
+
+		zoneID := rc.ZoneID
+		if zoneID == "" {
+			zoneID, err = client.ResolveZoneID(ctx, rc.Zone)
+			if err != nil {
+				return fmt.Errorf("failed to resolve zone %q: %w", rc.Zone, err)
+			}
+		}
+
+		recordID, err := client.ResolveOrCreateRecordID(ctx, zoneID, rc.RecordName, rc.Type, rc.ttl, rc.proxied)
+		if err != nil {
+			return fmt.Errorf("failed to resolve record %q in zone %q: %w", rc.RecordName, rc.Zone, err)
+		}
+
+		rc.ZoneID = zoneID
+		rc.RecordID = recordID
+		cache[key] = idCacheEntry{ZoneID: zoneID, RecordID: recordID}
+		changed = true
+	}
+
+	if changed {
+		if err := cache.save(cacheFile); err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn,
+				"failed to persist zone/record ID cache",
+				errAttr(err),
+			)
+		}
+	}
+
+	return nil
+}
+
 func run(ctx context.Context, logger *slog.Logger, cfg config) error {
 	const syncInterval = 4 * time.Hour
 
@@ -63,12 +155,59 @@ func run(ctx context.Context, logger *slog.Logger, cfg config) error {
 	tickTime := time.Now()
 	defer ticker.Stop()
 
+	var netChangeChan <-chan struct{}
+	if netChanges, err := newNetChangeWatcher(ctx, logger); err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn,
+			"failed to start network change watcher, falling back to ticker-only sync",
+			errAttr(err),
+		)
+	} else {
+		netChangeChan = netChanges.C()
+		defer func() {
+			if err := netChanges.Close(); err != nil {
+				logger.LogAttrs(ctx, slog.LevelWarn,
+					"failed to close network change watcher",
+					errAttr(err),
+				)
+			}
+		}()
+	}
+
 	var consecutiveFailCount int
 
-	syncFunc := newSyncFunc(&cfg, hc, func(r *http.Request) *http.Request {
+	policy := retryPolicy{base: cfg.retryBase, cap: syncInterval}
+
+	m := newMetrics()
+	shutdownMetrics, err := startMetricsServer(ctx, logger, cfg.MetricsAddr, m)
+	if err != nil {
+		const msg = "failed to start metrics server"
+		logger.LogAttrs(ctx, slog.LevelError,
+			msg,
+			errAttr(err),
+		)
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+
+		if err := shutdownMetrics(shutdownCtx); err != nil {
+			logger.LogAttrs(ctx, slog.LevelWarn,
+				"failed to cleanly shut down metrics server",
+				errAttr(err),
+			)
+		}
+	}()
+
+	reqDeco := func(r *http.Request) *http.Request {
 		r.Header.Set("User-Agent", "github.com---josephcopenhaver--cloudflare-dns-sync/1.0")
 		return r
-	})
+	}
+
+	syncFunc, recordSyncFuncs := newSyncFunc(&cfg, hc, reqDeco, m, nil)
+
+	reload := newReloadSource(ctx, logger, configFilePath())
+	defer reload.Close()
 
 	if err := ctx.Err(); err != nil {
 		logger.LogAttrs(ctx, slog.LevelWarn,
@@ -98,7 +237,10 @@ func run(ctx context.Context, logger *slog.Logger, cfg config) error {
 	)
 
 	doneChan := ctx.Done()
+	var retryChan <-chan time.Time
 	for {
+		m.recordAttempt()
+
 		if err := syncFunc(ctx, logger, tickTime); err != nil {
 			logger.LogAttrs(ctx, slog.LevelError,
 				"sync fail",
@@ -107,12 +249,30 @@ func run(ctx context.Context, logger *slog.Logger, cfg config) error {
 			)
 
 			consecutiveFailCount++
+			m.recordFailure(syncFailureReason(err))
+
+			if !isPermanent(err) {
+				delay := policy.nextDelay(consecutiveFailCount)
+				if d, ok := retryAfterFrom(err); ok && d > delay {
+					delay = d
+				}
+
+				if delay > 0 && delay < syncInterval {
+					logger.LogAttrs(ctx, slog.LevelInfo,
+						"scheduling early retry",
+						slog.String("delay", delay.String()),
+					)
+					retryChan = time.After(delay)
+				}
+			}
 		} else {
 			logger.LogAttrs(ctx, slog.LevelInfo,
 				"sync ok",
 			)
 
 			consecutiveFailCount = 0
+			retryChan = nil
+			m.recordSuccess(tickTime)
 		}
 
 		select {
@@ -131,153 +291,357 @@ func run(ctx context.Context, logger *slog.Logger, cfg config) error {
 		case <-doneChan:
 			return nil
 		case tickTime = <-ticker.C:
+		case <-netChangeChan:
+			logger.LogAttrs(ctx, slog.LevelInfo,
+				"network change detected, syncing early",
+			)
+			tickTime = time.Now()
+		case <-retryChan:
+			retryChan = nil
+			tickTime = time.Now()
+		case <-reload.C():
+			newCfg, err := reloadConfig(ctx, logger, hc, reqDeco)
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelError,
+					"failed to reload config, continuing with previous config",
+					errAttr(err),
+				)
+				continue
+			}
+
+			cfg = newCfg
+			policy = retryPolicy{base: cfg.retryBase, cap: syncInterval}
+			syncFunc, recordSyncFuncs = newSyncFunc(&cfg, hc, reqDeco, m, snapshotOldIP(recordSyncFuncs))
+
+			logger.LogAttrs(ctx, slog.LevelInfo,
+				"config reloaded",
+				slog.Int("record_count", len(recordSyncFuncs)),
+			)
+
+			tickTime = time.Now()
 		}
 	}
 }
 
-func newSyncFunc(cfg *config, hc *http.Client, reqDeco func(*http.Request) *http.Request) func(context.Context, *slog.Logger, time.Time) error {
-	apiToken := cfg.ApiToken
+// reloadConfig re-loads and validates the config from disk/environment and
+// resolves any newly configured records' zone/record IDs, mirroring the
+// startup path in Run so a reload behaves identically to a fresh start.
+func reloadConfig(ctx context.Context, logger *slog.Logger, hc *http.Client, reqDeco func(*http.Request) *http.Request) (config, error) {
+	cfg, err := newConfig(ctx, logger)
+	if err != nil {
+		return config{}, fmt.Errorf("failed to load runtime config: %w", err)
+	}
 
-	var reqBodyStrPrefix string
-	{
-		var buf bytes.Buffer
-		if err := json.NewEncoder(&buf).Encode(cfg.RecordName); err != nil {
-			panic(fmt.Errorf("failed to json encode record name: %w", err))
+	if err := resolveConfigIDs(ctx, logger, &cfg, hc, reqDeco); err != nil {
+		return config{}, fmt.Errorf("failed to resolve zone/record IDs: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// recordSyncFunc applies a single IP address to a single configured DNS
+// record, tracking the last IP it successfully pushed so repeat ticks with
+// an unchanged address are a no-op.
+type recordSyncFunc struct {
+	zone, zoneID, recordID, recordName, recordType string
+	ttl                                             int
+	proxied                                         bool
+	family                                          ipFamily
+
+	reqBodyStrPrefix string
+	reqBodyStrSuffix string
+	setRecordBaseReq *http.Request
+
+	cfClient  *cfClient
+	cacheFile string
+
+	oldIP string
+}
+
+// identityKey identifies a record independent of its resolved Cloudflare
+// IDs, so that a config reload can recognize "the same record" even if its
+// zone_id/record_id were re-resolved in between, and carry its oldIP
+// forward instead of re-pushing an unchanged address.
+func (s *recordSyncFunc) identityKey() string {
+	return idCacheKey(s.zone, s.recordName, s.recordType)
+}
+
+func newRecordSyncFunc(rc recordConfig, cfClient *cfClient, cacheFile string) (*recordSyncFunc, error) {
+	s := &recordSyncFunc{
+		zone:       rc.Zone,
+		zoneID:     rc.ZoneID,
+		recordID:   rc.RecordID,
+		recordName: rc.RecordName,
+		recordType: rc.Type,
+		ttl:        rc.ttl,
+		proxied:    rc.proxied,
+		family:     ipFamilyV4,
+		cfClient:   cfClient,
+		cacheFile:  cacheFile,
+	}
+
+	if rc.Type == "AAAA" {
+		s.family = ipFamilyV6
+	}
+
+	if err := s.rebuildRequestTemplates(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// rebuildRequestTemplates (re)builds the PUT request template from the
+// record's current zoneID/recordID, used both at construction time and
+// after a successful re-resolution following a 404 from the Cloudflare API.
+func (s *recordSyncFunc) rebuildRequestTemplates() error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(s.recordName); err != nil {
+		return fmt.Errorf("failed to json encode record name: %w", err)
+	}
+
+	s.reqBodyStrPrefix = `{"type":"` + s.recordType + `","name":` + strings.TrimSuffix(buf.String(), "\n") + `,"content":`
+	s.reqBodyStrSuffix = `,"ttl":` + strconv.Itoa(s.ttl) + `,"proxied":` + strconv.FormatBool(s.proxied) + `}`
+
+	req, err := http.NewRequest(http.MethodPut, "https://api.cloudflare.com/client/v4/zones/"+url.PathEscape(s.zoneID)+"/dns_records/"+url.PathEscape(s.recordID), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create base request for setting DNS record: %w", err)
+	}
+	s.setRecordBaseReq = req
+
+	return nil
+}
+
+// reresolve re-discovers the zone/record IDs after the Cloudflare API
+// reports the previously cached record as gone (HTTP 404), updating the
+// on-disk ID cache so future starts pick up the new IDs.
+func (s *recordSyncFunc) reresolve(ctx context.Context) error {
+	if s.cfClient == nil {
+		return errors.New("no cloudflare client available to re-resolve IDs")
+	}
+
+	zoneID := s.zoneID
+	if s.zone != "" {
+		var err error
+		zoneID, err = s.cfClient.ResolveZoneID(ctx, s.zone)
+		if err != nil {
+			return fmt.Errorf("failed to resolve zone %q: %w", s.zone, err)
 		}
+	}
 
-		reqBodyStrPrefix = `{"type":"A","name":` + strings.TrimSuffix(buf.String(), "\n") + `,"content":`
+	recordID, err := s.cfClient.ResolveOrCreateRecordID(ctx, zoneID, s.recordName, s.recordType, s.ttl, s.proxied)
+	if err != nil {
+		return fmt.Errorf("failed to resolve record %q in zone %q: %w", s.recordName, s.zone, err)
 	}
 
-	reqBodyStrSuffix := `,"ttl":` + strconv.Itoa(cfg.ttl) + `,"proxied":false}`
+	s.zoneID = zoneID
+	s.recordID = recordID
+	if err := s.rebuildRequestTemplates(); err != nil {
+		return err
+	}
 
-	readIPBaseReq, err := http.NewRequest(http.MethodGet, "https://checkip.amazonaws.com/", http.NoBody)
+	cache, err := loadIDCache(s.cacheFile)
 	if err != nil {
-		panic(err)
+		cache = idCache{}
 	}
+	cache[idCacheKey(s.zone, s.recordName, s.recordType)] = idCacheEntry{ZoneID: zoneID, RecordID: recordID}
+	return cache.save(s.cacheFile)
+}
+
+func (s *recordSyncFunc) logAttrs() []slog.Attr {
+	return []slog.Attr{
+		slog.String("zone_id", s.zoneID),
+		slog.String("record_id", s.recordID),
+		slog.String("record_name", s.recordName),
+	}
+}
 
-	setRecordBaseReq, err := http.NewRequest(http.MethodPut, "https://api.cloudflare.com/client/v4/zones/"+url.PathEscape(cfg.ZoneID)+"/dns_records/"+url.PathEscape(cfg.RecordID), http.NoBody)
+// putRecord makes a single attempt at pushing the current IP to Cloudflare,
+// returning the response status code alongside any error so callers can
+// decide whether a 404 warrants re-resolving the record's IDs.
+func (s *recordSyncFunc) putRecord(ctx context.Context, apiToken string, hc *http.Client, reqDeco func(*http.Request) *http.Request, jsonIP string) (int, error) {
+	req := s.setRecordBaseReq.Clone(ctx)
+	req.Body = io.NopCloser(strings.NewReader(s.reqBodyStrPrefix + jsonIP + s.reqBodyStrSuffix))
+	req.GetBody = nil
+
+	h := req.Header
+	h.Set("Content-Type", "application/json")
+	h.Set("Authorization", "Bearer "+apiToken)
+	req = reqDeco(req)
+
+	resp, err := hc.Do(req)
 	if err != nil {
-		panic(fmt.Errorf("failed to create base request for setting DNS record: %w", err))
+		return 0, fmt.Errorf("failed to get response from cloudflare api: %w", err)
 	}
+	defer resp.Body.Close()
 
-	var oldIP string
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		const msg = "unexpected response status code from cloudflare"
+
+		if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+			return resp.StatusCode, fmt.Errorf("%s: %d (and failed to read response body: %w)", msg, resp.StatusCode, err)
+		}
 
-	return func(ctx context.Context, logger *slog.Logger, t time.Time) error {
+		syncErr := errors.New(msg + ": " + strconv.Itoa(resp.StatusCode))
+		return resp.StatusCode, classifyHTTPError(syncErr, resp.StatusCode, resp.Header)
+	}
+
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read full success response body from cloudflare: %w", err)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (s *recordSyncFunc) sync(ctx context.Context, logger *slog.Logger, apiToken string, hc *http.Client, reqDeco func(*http.Request) *http.Request, ip, jsonIP string) error {
+	if ip == s.oldIP {
 		logger.LogAttrs(ctx, slog.LevelInfo,
-			"sync running",
-			slog.Int64("tick_time", t.UnixNano()),
+			"same IP address",
+			append(s.logAttrs(), slog.String("ip", ip))...,
 		)
+		return nil
+	}
 
-		var ip, jsonIP string
-		err := func() error {
-			req := reqDeco(readIPBaseReq.Clone(ctx))
-			resp, err := hc.Do(req)
-			if err != nil {
-				return fmt.Errorf("failed to get response from checkip.amazonaws.com: %w", err)
-			}
-			defer resp.Body.Close()
+	logger.LogAttrs(ctx, slog.LevelInfo,
+		"new IP address",
+		append(s.logAttrs(), slog.String("ip_old", s.oldIP), slog.String("ip_new", ip))...,
+	)
 
-			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-				if _, err := io.Copy(io.Discard, req.Body); err != nil {
-					return err
-				}
+	err := func() error {
+		statusCode, err := s.putRecord(ctx, apiToken, hc, reqDeco, jsonIP)
+		if err == nil {
+			return nil
+		}
 
-				return errors.New("response status code from checkip.amazonaws.com is not in 2xx range: " + strconv.Itoa(resp.StatusCode))
-			}
+		if statusCode != http.StatusNotFound || s.cfClient == nil {
+			return err
+		}
 
-			b, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return err
-			}
+		logger.LogAttrs(ctx, slog.LevelWarn,
+			"record not found, re-resolving zone/record IDs",
+			append(s.logAttrs(), errAttr(err))...,
+		)
 
-			ip = strings.TrimSpace(string(b))
-			if net.ParseIP(ip) == nil {
-				return errors.New("checkip.amazonaws.com failed to return a valid IP address in response body")
-			}
+		if rerr := s.reresolve(ctx); rerr != nil {
+			return fmt.Errorf("%w (re-resolve also failed: %s)", err, rerr)
+		}
 
-			var buf bytes.Buffer
-			if err := json.NewEncoder(&buf).Encode(ip); err != nil {
-				return fmt.Errorf("failed to json encode ip: %w", err)
-			}
-			jsonIP = strings.TrimSuffix(buf.String(), "\n")
+		_, err = s.putRecord(ctx, apiToken, hc, reqDeco, jsonIP)
+		return err
+	}()
+	if err != nil {
+		const msg = "failed to verify Cloudflare DNS record was updated"
+		logger.LogAttrs(ctx, slog.LevelError,
+			msg,
+			append(s.logAttrs(), errAttr(err))...,
+		)
+		return fmt.Errorf("%s: %w", msg, err)
+	}
 
-			return nil
-		}()
+	s.oldIP = ip
+	return nil
+}
+
+// newSyncFunc builds the tick handler for the configured records. prevOldIP
+// carries forward the last-pushed IP per record identity (see
+// recordSyncFunc.identityKey) across a config reload, so that records whose
+// identity didn't change don't get re-pushed on the next tick just because
+// the service rebuilt its sync state. The returned slice lets a future
+// reload snapshot state out of this generation in turn.
+func newSyncFunc(cfg *config, hc *http.Client, reqDeco func(*http.Request) *http.Request, m *metrics, prevOldIP map[string]string) (func(context.Context, *slog.Logger, time.Time) error, []*recordSyncFunc) {
+	apiToken := cfg.ApiToken
+
+	client := newCFClient(hc, apiToken, reqDeco)
+	cacheFile := idCacheFilePath(configDir())
+
+	recordSyncFuncs := make([]*recordSyncFunc, len(cfg.Records))
+	families := make(map[ipFamily]bool, 2)
+	for i := range cfg.Records {
+		rsf, err := newRecordSyncFunc(cfg.Records[i], client, cacheFile)
 		if err != nil {
-			const msg = "failed to determine IP address"
-			logger.LogAttrs(ctx, slog.LevelError,
-				msg,
-				errAttr(err),
-			)
-			return fmt.Errorf("%s: %w", msg, err)
+			panic(err)
 		}
-
-		if ip == oldIP {
-			logger.LogAttrs(ctx, slog.LevelInfo,
-				"same IP address",
-				slog.String("ip", ip),
-			)
-			return nil
+		if oldIP, ok := prevOldIP[rsf.identityKey()]; ok {
+			rsf.oldIP = oldIP
 		}
+		recordSyncFuncs[i] = rsf
+		families[rsf.family] = true
+	}
+
+	providersByFamily := make(map[ipFamily][]IPProvider, 2)
+	if families[ipFamilyV4] {
+		providersByFamily[ipFamilyV4] = mustIPProviders(cfg.IPv4Providers, ipFamilyV4)
+	}
+	if families[ipFamilyV6] {
+		providersByFamily[ipFamilyV6] = mustIPProviders(cfg.IPv6Providers, ipFamilyV6)
+	}
 
+	fn := func(ctx context.Context, logger *slog.Logger, t time.Time) error {
 		logger.LogAttrs(ctx, slog.LevelInfo,
-			"new IP address",
-			slog.String("ip_old", oldIP),
-			slog.String("ip_new", ip),
+			"sync running",
+			slog.Int64("tick_time", t.UnixNano()),
+			slog.Int("record_count", len(recordSyncFuncs)),
 		)
 
-		err = func() error {
-			req := setRecordBaseReq.Clone(ctx)
-			req.Body = io.NopCloser(strings.NewReader(reqBodyStrPrefix + jsonIP + reqBodyStrSuffix))
-			req.GetBody = nil
-
-			h := req.Header
-			h.Set("Content-Type", "application/json")
-			h.Set("Authorization", "Bearer "+apiToken)
-			req = reqDeco(req)
-
-			resp, err := hc.Do(req)
+		jsonIPByFamily := make(map[ipFamily]string, len(providersByFamily))
+		ipByFamily := make(map[ipFamily]net.IP, len(providersByFamily))
+		for family, providers := range providersByFamily {
+			ip, err := lookupIPWithFallback(ctx, logger, providers, hc, reqDeco)
 			if err != nil {
-				return fmt.Errorf("failed to get response from cloudflare api: %w", err)
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-				const msg = "unexpected response status code from cloudflare"
+				const msg = "failed to determine IP address"
 				logger.LogAttrs(ctx, slog.LevelError,
 					msg,
-					slog.Int("status_code", resp.StatusCode),
+					slog.Int("family", int(family)),
+					errAttr(err),
 				)
+				return fmt.Errorf("%s: %w", msg, err)
+			}
 
-				if _, err := io.Copy(io.Discard, req.Body); err != nil {
-					logger.LogAttrs(ctx, slog.LevelError,
-						"failed to read full non-success response body from cloudflare",
-						errAttr(err),
-					)
-				}
-
-				return errors.New(msg + ": " + strconv.Itoa(resp.StatusCode))
+			var buf bytes.Buffer
+			if err := json.NewEncoder(&buf).Encode(ip.String()); err != nil {
+				return fmt.Errorf("failed to json encode ip: %w", err)
 			}
 
-			if _, err := io.Copy(io.Discard, req.Body); err != nil {
-				logger.LogAttrs(ctx, slog.LevelWarn,
-					"failed to read full success response body from cloudflare",
-					errAttr(err),
-				)
+			ipByFamily[family] = ip
+			jsonIPByFamily[family] = strings.TrimSuffix(buf.String(), "\n")
+			m.recordIP(familyLabel(family), ip.String())
+		}
+
+		var errs []error
+		for _, rsf := range recordSyncFuncs {
+			ip := ipByFamily[rsf.family].String()
+			jsonIP := jsonIPByFamily[rsf.family]
+
+			if err := rsf.sync(ctx, logger, apiToken, hc, reqDeco, ip, jsonIP); err != nil {
+				errs = append(errs, err)
 			}
+		}
 
-			return nil
-		}()
+		return errors.Join(errs...)
+	}
+
+	return fn, recordSyncFuncs
+}
+
+// snapshotOldIP captures the last-pushed IP for each record, keyed by
+// identity, so it can be threaded through a rebuilt newSyncFunc after a
+// config reload.
+func snapshotOldIP(recordSyncFuncs []*recordSyncFunc) map[string]string {
+	m := make(map[string]string, len(recordSyncFuncs))
+	for _, rsf := range recordSyncFuncs {
+		m[rsf.identityKey()] = rsf.oldIP
+	}
+	return m
+}
+
+func mustIPProviders(names []string, family ipFamily) []IPProvider {
+	providers := make([]IPProvider, len(names))
+	for i, name := range names {
+		p, err := newIPProviderByName(name, family)
 		if err != nil {
-			const msg = "failed to verify Cloudflare DNS record was updated"
-			logger.LogAttrs(ctx, slog.LevelError,
-				msg,
-				errAttr(err),
-			)
-			return fmt.Errorf("%s: %w", msg, err)
+			panic(err)
 		}
-
-		oldIP = ip
-		return nil
+		providers[i] = p
 	}
+	return providers
 }
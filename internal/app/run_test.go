@@ -0,0 +1,66 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSnapshotOldIPKeyedByIdentity(t *testing.T) {
+	rc := recordConfig{Zone: "example.com", RecordName: "home.example.com", Type: "A", ttl: 120}
+	rsf, err := newRecordSyncFunc(rc, nil, "")
+	if err != nil {
+		t.Fatalf("newRecordSyncFunc() error = %v", err)
+	}
+	rsf.oldIP = "203.0.113.9"
+
+	snap := snapshotOldIP([]*recordSyncFunc{rsf})
+
+	key := idCacheKey("example.com", "home.example.com", "A")
+	if got := snap[key]; got != "203.0.113.9" {
+		t.Errorf("snapshotOldIP()[%q] = %q, want %q", key, got, "203.0.113.9")
+	}
+}
+
+func TestNewSyncFuncCarriesOldIPAcrossReloadByIdentity(t *testing.T) {
+	cfg := &config{
+		Records: []recordConfig{
+			{Zone: "example.com", RecordName: "home.example.com", Type: "A", ttl: 120},
+		},
+		IPv4Providers: []string{"os"},
+	}
+
+	prevOldIP := map[string]string{
+		idCacheKey("example.com", "home.example.com", "A"): "203.0.113.5",
+	}
+
+	_, recordSyncFuncs := newSyncFunc(cfg, &http.Client{}, func(r *http.Request) *http.Request { return r }, newMetrics(), prevOldIP)
+
+	if len(recordSyncFuncs) != 1 {
+		t.Fatalf("newSyncFunc() returned %d record sync funcs, want 1", len(recordSyncFuncs))
+	}
+	if got := recordSyncFuncs[0].oldIP; got != "203.0.113.5" {
+		t.Errorf("recordSyncFuncs[0].oldIP = %q, want %q (not carried across reload by identity)", got, "203.0.113.5")
+	}
+}
+
+func TestNewSyncFuncDoesNotCarryOldIPForDifferentIdentity(t *testing.T) {
+	cfg := &config{
+		Records: []recordConfig{
+			{Zone: "example.com", RecordName: "home.example.com", Type: "A", ttl: 120},
+		},
+		IPv4Providers: []string{"os"},
+	}
+
+	prevOldIP := map[string]string{
+		idCacheKey("example.com", "other.example.com", "A"): "203.0.113.5",
+	}
+
+	_, recordSyncFuncs := newSyncFunc(cfg, &http.Client{}, func(r *http.Request) *http.Request { return r }, newMetrics(), prevOldIP)
+
+	if len(recordSyncFuncs) != 1 {
+		t.Fatalf("newSyncFunc() returned %d record sync funcs, want 1", len(recordSyncFuncs))
+	}
+	if got := recordSyncFuncs[0].oldIP; got != "" {
+		t.Errorf("recordSyncFuncs[0].oldIP = %q, want empty for an unrelated identity", got)
+	}
+}